@@ -6,21 +6,25 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/hyperjumptech/acccore"
 	"github.com/hyperjumptech/bookkeeping/internal/accounting"
-	"github.com/hyperjumptech/bookkeeping/internal/firebase"
+	"github.com/hyperjumptech/bookkeeping/internal/backup"
 	"github.com/robfig/cron/v3"
 
 	"github.com/gorilla/mux"
 	"github.com/hyperjumptech/bookkeeping/internal/config"
 	"github.com/hyperjumptech/bookkeeping/internal/connector"
 	"github.com/hyperjumptech/bookkeeping/internal/health"
+	"github.com/hyperjumptech/bookkeeping/internal/leader"
 	"github.com/hyperjumptech/bookkeeping/internal/logger"
+	"github.com/hyperjumptech/bookkeeping/internal/observability"
 	"github.com/hyperjumptech/bookkeeping/internal/router"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 )
 
 var (
@@ -45,6 +49,30 @@ var (
 
 	// cron timer
 	cr *cron.Cron
+
+	// backupSched schedules and runs backup jobs against registered destinations
+	backupSched *backup.BackupScheduler
+
+	// appServer coordinates graceful shutdown across subsystems
+	appServer *Server
+
+	// routerPtr holds the live *mux.Router. HTTPServer.Handler reads through
+	// it on every request, so a SIGHUP reload can swap in a freshly built
+	// router without dropping connections.
+	routerPtr atomic.Pointer[mux.Router]
+
+	// IntrospectionServer exposes /metrics, /debug/pprof/*, /healthz, /readyz
+	// on a private listener, separate from the public API address.
+	IntrospectionServer *http.Server
+
+	// challengeServer answers ACME HTTP-01 challenges and redirects plain
+	// HTTP to HTTPS when server.tls.mode is "autocert". nil otherwise.
+	challengeServer *http.Server
+
+	// elector decides which replica of the service is allowed to run the
+	// backup cron schedule, so replicas behind a load balancer don't all
+	// dump and upload at once.
+	elector *leader.MySQLElector
 )
 
 // InitializeServer initializes all server connections
@@ -64,6 +92,10 @@ func InitializeServer() error {
 	logf.Info("setting up routing...")
 	appRouter = router.NewRouter()
 	appRouter.Router = mux.NewRouter()
+	// Use, not a wrapper around HTTPServer.Handler: gorilla mux only attaches
+	// the matched route to the request inside its own ServeHTTP, so a
+	// middleware installed outside the router never sees mux.CurrentRoute.
+	appRouter.Router.Use(observability.Middleware)
 
 	// setup db connection
 	dbRepo = &connector.MySQLDBRepository{}
@@ -90,8 +122,31 @@ func InitializeServer() error {
 		logf.Warn("health monitor error: ", err)
 	}
 
+	// leader election: only the winner of this lock runs the backup
+	// schedule, so replicas behind a load balancer don't double-run it.
+	elector = leader.NewMySQLElector(dbRepo.DB().DB, "bookkeeping.cron", time.Duration(config.GetInt("leader.election.refresh_interval_seconds"))*time.Second)
+	if err := elector.Start(context.Background()); err != nil {
+		logf.Error("could not start leader election: ", err)
+	}
+
+	// cron scheduler setup, ahead of route registration so the admin backup
+	// routes below have a scheduler to bind to
+	cr = cron.New()
+	if err := initBackupScheduler(); err != nil {
+		logf.Error("could not initialize backup scheduler: ", err)
+	}
+	if backupSched != nil {
+		backupSched.Metrics = observability.BackupMetrics{}
+		backupSched.Elector = elector
+	}
+	cr.Start()
+
 	logf.Info("initializing routes...")
 	router.InitRoutes(appRouter)
+	if backupSched != nil {
+		backup.RegisterAdminRoutes(appRouter.Router, backupSched)
+	}
+	routerPtr.Store(appRouter.Router)
 
 	address = fmt.Sprintf("%s:%s", config.Get("server.host"), config.Get("server.port"))
 	HTTPServer = &http.Server{
@@ -99,14 +154,22 @@ func InitializeServer() error {
 		WriteTimeout: time.Second * 15, // Good practice to set timeouts to avoid Slowloris attacks.
 		ReadTimeout:  time.Second * 15,
 		IdleTimeout:  time.Second * 60,
-		Handler:      appRouter.Router, // Pass our instance of gorilla/mux in.
+		// Handler reads through routerPtr so reloadConfig can hot-swap the
+		// router on SIGHUP without restarting the listener. Metrics come
+		// from observability.Middleware, installed via Router.Use above (and
+		// again in reloadRouter) so it runs inside mux's own handler chain
+		// where mux.CurrentRoute is actually populated.
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			routerPtr.Load().ServeHTTP(w, r)
+		}),
 	}
 
-	// cron scheduler setup
-	cr = cron.New()
-	fmt.Println("schedule is: ", config.Get("cron.backup.daily"))
-	cr.AddFunc(config.Get("cron.backup.daily"), func() { cronBackupUpload(context.Background()) })
-	cr.Start()
+	introspectionAddr := fmt.Sprintf("%s:%s", config.Get("server.introspection.host"), config.Get("server.introspection.port"))
+	IntrospectionServer = observability.NewIntrospectionServer(introspectionAddr, func() error {
+		return dbRepo.DB().DB.Ping()
+	}, elector.IsLeader)
+
+	challengeServer = configureTLS()
 
 	// indicate if dev or production mode
 	env := config.Get("app.env")
@@ -117,54 +180,221 @@ func InitializeServer() error {
 		logf.Warn("environment is: ", env)
 	}
 
+	// register shutdown hooks so they're torn down in this order: the
+	// ACME challenge listener (autocert mode only) and the introspection
+	// listener go first, then cron must stop taking new backups, then the
+	// HTTP listener can drain in-flight requests, then the leader election
+	// lock is released, and only once that's done is the db connection
+	// closed. AddShutdownHook runs hooks in reverse registration order, so
+	// they're registered here db-first, leader-election-second, then http,
+	// cron, introspection, and tls-challenge last.
+	appServer = &Server{}
+	appServer.AddShutdownHook("db", func(ctx context.Context) error {
+		return dbRepo.DB().DB.Close()
+	})
+	appServer.AddShutdownHook("leader-election", func(ctx context.Context) error {
+		return elector.Stop(ctx)
+	})
+	appServer.AddShutdownHook("http", func(ctx context.Context) error {
+		return HTTPServer.Shutdown(ctx)
+	})
+	appServer.AddShutdownHook("cron", func(ctx context.Context) error {
+		cronCtx := cr.Stop()
+		select {
+		case <-cronCtx.Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	appServer.AddShutdownHook("introspection", func(ctx context.Context) error {
+		return IntrospectionServer.Shutdown(ctx)
+	})
+	if challengeServer != nil {
+		appServer.AddShutdownHook("tls-challenge", func(ctx context.Context) error {
+			return challengeServer.Shutdown(ctx)
+		})
+	}
+
 	return nil
 }
 
-// shutdownServer handles shutdown gracefully, clossing connections, flushing caches etc.
+// shutdownServer drains every registered subsystem via appServer, bounded by
+// the server.shutdown.timeout config.
 func shutdownServer() error {
 	logf := srvLog.WithField("fn", "shutdownServer")
 
-	dbRepo.DB().DB.Close()
-	logf.Info("done: db closed")
+	timeout := time.Duration(config.GetInt("server.shutdown.timeout")) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	cr.Stop()
-	logf.Info("done: cron stopped")
+	if err := appServer.Shutdown(ctx); err != nil {
+		logf.Error("shutdown did not complete cleanly: ", err)
+		return err
+	}
 
+	logf.Info("done: all subsystems drained")
 	return nil
 }
 
-// cronBackupUpload() runs periodically to dump db and upload to storage backup
-func cronBackupUpload(ctx context.Context) error {
-	logf := srvLog.WithField("fn", "cronBackupUpload")
+// initBackupScheduler builds the backup destination configured via
+// backup.destination, registers the daily full and (if configured) hourly
+// incremental jobs on cr, and stores the scheduler in backupSched so the
+// admin routes and a SIGHUP reload can reach it.
+func initBackupScheduler() error {
+	backupSched = backup.NewBackupScheduler(cr, dbRepo.DumpDB)
+	return registerBackupJobs(backupSched)
+}
 
-	file, err := dbRepo.DumpDB(ctx)
+// buildBackupJobs constructs the daily-full and, if configured,
+// hourly-incremental jobs from the current config without registering them
+// on a scheduler. Keeping this side-effect-free lets callers fully validate
+// a config change (destination connectivity, encryption recipients, SFTP
+// credentials) before committing it to a live schedule - see
+// reloadBackupSchedule.
+func buildBackupJobs() ([]backup.Job, error) {
+	dest, err := newConfiguredDestination()
 	if err != nil {
-		logf.Error("failed to dump db to file, got: ", err)
-		if err = os.Remove(file); err != nil {
-			logf.Error("coudn't remove file, got: ", err)
-		}
-		return err
+		return nil, err
 	}
-	if err = firebase.Upload(ctx, file); err != nil {
-		logf.Error("failed to upload file, got: ", err)
-		if err = os.Remove(file); err != nil {
-			logf.Error("coudn't remove file, got: ", err)
-		}
-		return err
+
+	encryptor, err := newConfiguredEncryptor()
+	if err != nil {
+		return nil, err
+	}
+
+	retention := backup.Retention{
+		KeepLatest: config.GetInt("backup.retention.keep_latest"),
+		MaxAge:     time.Duration(config.GetInt("backup.retention.max_age_days")) * 24 * time.Hour,
+	}
+
+	jobs := []backup.Job{{
+		Name:        "daily-full",
+		CronSpec:    config.Get("cron.backup.daily"),
+		Destination: dest,
+		Retention:   retention,
+		Encryptor:   encryptor,
+	}}
+
+	if hourly := config.Get("cron.backup.hourly"); hourly != "" {
+		jobs = append(jobs, backup.Job{
+			Name:        "hourly-incremental",
+			CronSpec:    hourly,
+			Destination: dest,
+			Retention:   retention,
+			Encryptor:   encryptor,
+		})
 	}
-	if err = os.Remove(file); err != nil {
-		logf.Error("coudn't remove file, got: ", err)
+
+	return jobs, nil
+}
+
+// registerBackupJobs (re-)registers the daily-full and, if configured,
+// hourly-incremental jobs against sched from the current config. Callers
+// that are rescheduling (e.g. a SIGHUP reload) must Unregister the existing
+// job names first.
+func registerBackupJobs(sched *backup.BackupScheduler) error {
+	logf := srvLog.WithField("fn", "registerBackupJobs")
+
+	jobs, err := buildBackupJobs()
+	if err != nil {
 		return err
 	}
 
-	logf.Info("success cleaning up: ", file)
+	for _, job := range jobs {
+		if err := sched.Register(job); err != nil {
+			return err
+		}
+		logf.Info("registered backup job: ", job.Name, " (", job.CronSpec, ")")
+	}
+
 	return nil
 }
 
+// newConfiguredDestination builds the backup.Destination selected by
+// backup.destination ("firebase", "s3", "local", or "sftp").
+func newConfiguredDestination() (backup.Destination, error) {
+	switch config.Get("backup.destination") {
+	case "s3":
+		return backup.NewS3Destination(context.Background(), config.Get("backup.s3.bucket"), config.Get("backup.s3.prefix"), config.Get("backup.s3.endpoint"))
+	case "local":
+		return backup.NewLocalDestination(config.Get("backup.local.dir"))
+	case "sftp":
+		return newConfiguredSFTPDestination()
+	case "firebase", "":
+		return backup.NewFirebaseDestination(), nil
+	default:
+		return nil, fmt.Errorf("backup: unknown backup.destination %q", config.Get("backup.destination"))
+	}
+}
+
+// newConfiguredSFTPDestination builds the sftp backup.Destination from
+// backup.sftp.addr/user/dir, authenticating with
+// backup.sftp.private_key_file if set, falling back to backup.sftp.password.
+func newConfiguredSFTPDestination() (backup.Destination, error) {
+	var auth []ssh.AuthMethod
+	if keyFile := config.Get("backup.sftp.private_key_file"); keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("backup: reading sftp private key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("backup: parsing sftp private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	} else {
+		auth = append(auth, ssh.Password(config.Get("backup.sftp.password")))
+	}
+
+	hostKeyCallback, err := sftpHostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            config.Get("backup.sftp.user"),
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         10 * time.Second,
+	}
+
+	return backup.NewSFTPDestination(config.Get("backup.sftp.addr"), sshConfig, config.Get("backup.sftp.dir"))
+}
+
+// sftpHostKeyCallback pins the remote host key from backup.sftp.host_key
+// (an authorized_keys-format public key, e.g. "ssh-ed25519 AAAA..."). Backups
+// carry full database dumps, so an unset host key is refused rather than
+// silently falling back to ssh.InsecureIgnoreHostKey - operators must supply
+// the key before sftp can be used as a destination.
+func sftpHostKeyCallback() (ssh.HostKeyCallback, error) {
+	raw := config.Get("backup.sftp.host_key")
+	if raw == "" {
+		return nil, fmt.Errorf("backup: backup.sftp.host_key is required to use sftp as a destination")
+	}
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("backup: parsing backup.sftp.host_key: %w", err)
+	}
+	return ssh.FixedHostKey(key), nil
+}
+
+// newConfiguredEncryptor builds the optional age Encryptor from
+// backup.encryption.recipients, a comma-separated list of age recipient
+// strings (e.g. "age1..."). Returns a nil Encryptor, not an error, when the
+// key is unset - encryption is opt-in.
+func newConfiguredEncryptor() (backup.Encryptor, error) {
+	recipients := splitCSV(config.Get("backup.encryption.recipients"))
+	if len(recipients) == 0 {
+		return nil, nil
+	}
+	return backup.NewAgeEncryptor(recipients)
+}
+
 // StartServer starts listening at given port
 func StartServer() {
 
-	var wait time.Duration
 	logf := srvLog.WithField("fn", "StartServer")
 
 	logf.Info("initializing server...")
@@ -172,13 +402,37 @@ func StartServer() {
 	if err != nil {
 		logf.Error(err)
 	}
-	defer shutdownServer()
 
 	logf.Info("starting server...")
 	logf.Info("App version: ", config.Get("app.version"), ", listening at: ", address)
 	// Run our server in a goroutine so that it doesn't block.
 	go func() {
-		if err := HTTPServer.ListenAndServe(); err != nil {
+		var err error
+		switch config.Get("server.tls.mode") {
+		case "autocert":
+			err = HTTPServer.ListenAndServeTLS("", "")
+		case "manual":
+			err = HTTPServer.ListenAndServeTLS(config.Get("server.tls.cert_file"), config.Get("server.tls.key_file"))
+		default:
+			err = HTTPServer.ListenAndServe()
+		}
+		if err != nil {
+			logf.Error(err)
+		}
+	}()
+
+	if challengeServer != nil {
+		logf.Info("starting ACME challenge/HTTPS-redirect server at: ", challengeServer.Addr)
+		go func() {
+			if err := challengeServer.ListenAndServe(); err != nil {
+				logf.Error(err)
+			}
+		}()
+	}
+
+	logf.Info("starting introspection server at: ", IntrospectionServer.Addr)
+	go func() {
+		if err := IntrospectionServer.ListenAndServe(); err != nil {
 			logf.Error(err)
 		}
 	}()
@@ -190,18 +444,24 @@ func StartServer() {
 	signal.Notify(gracefulStop, syscall.SIGTERM)
 	signal.Notify(gracefulStop, syscall.SIGINT)
 
-	// Block until we receive our signal.
+	// SIGHUP reloads config in place instead of tearing the process down.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloadConfig()
+		}
+	}()
+
+	// Block until we receive our shutdown signal.
 	<-gracefulStop
+	signal.Stop(reload)
 
-	// Create a deadline to wait for.
-	ctx, cancel := context.WithTimeout(context.Background(), wait)
-	defer cancel()
-	// Doesn't block if no connections, but will otherwise wait
-	// until the timeout deadline.
-	HTTPServer.Shutdown(ctx)
-	// Optionally, you could run srv.Shutdown in a goroutine and block on
-	// <-ctx.Done() if your application should wait for other services
-	// to finalize based on context cancellation.
+	// shutdownServer drives the whole drain sequence (cron, then HTTP, then
+	// db) from a single context.WithTimeout bounded by server.shutdown.timeout.
+	if err := shutdownServer(); err != nil {
+		logf.Error(err)
+	}
 	logf.Info("shutting down........ bye")
 
 	t := time.Now()
@@ -0,0 +1,45 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Artifact describes a single backup object living at a Destination.
+type Artifact struct {
+	// Name is the remote object name/key, "<jobName>/<file name>", e.g. "daily-full/dump.sql.gz"
+	Name string
+	// Size in bytes
+	Size int64
+	// ModifiedAt is the artifact's last-modified timestamp as reported by the destination
+	ModifiedAt time.Time
+}
+
+// Destination is implemented by every storage backend a backup can be shipped to.
+// Implementations must be safe for concurrent use.
+type Destination interface {
+	// Name identifies the destination in logs and the admin API, e.g. "firebase", "s3", "local", "sftp"
+	Name() string
+
+	// Upload ships the file at localPath to the destination under remoteName.
+	Upload(ctx context.Context, localPath string, remoteName string) error
+
+	// List enumerates artifacts currently stored at the destination for the
+	// named job, most recent first. Jobs sharing a Destination must not see
+	// each other's artifacts, the same way Upload scopes remoteName by job.
+	List(ctx context.Context, jobName string) ([]Artifact, error)
+
+	// Delete removes the named artifact from the destination.
+	Delete(ctx context.Context, remoteName string) error
+}
+
+// ErrNotSupported is returned by Destination methods that a backend cannot implement.
+type ErrNotSupported struct {
+	Destination string
+	Op          string
+}
+
+func (e *ErrNotSupported) Error() string {
+	return fmt.Sprintf("backup: %s does not support %s", e.Destination, e.Op)
+}
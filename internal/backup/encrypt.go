@@ -0,0 +1,64 @@
+package backup
+
+import (
+	"io"
+	"os"
+
+	"filippo.io/age"
+)
+
+// Encryptor encrypts a dump file before it leaves the host, so that a
+// compromised destination never holds a plaintext copy of the database.
+type Encryptor interface {
+	// Encrypt reads src and writes the encrypted form to dst, returning dst's path.
+	Encrypt(src string) (dst string, err error)
+}
+
+// ageEncryptor encrypts backups to age X25519 recipients ("age1..." public
+// keys). SSH public keys are not accepted as recipients.
+type ageEncryptor struct {
+	recipients []age.Recipient
+}
+
+// NewAgeEncryptor builds an Encryptor for the given age recipient strings
+// (e.g. "age1..." public keys).
+func NewAgeEncryptor(recipientStrings []string) (Encryptor, error) {
+	recipients := make([]age.Recipient, 0, len(recipientStrings))
+	for _, r := range recipientStrings {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return &ageEncryptor{recipients: recipients}, nil
+}
+
+// Encrypt implements Encryptor.
+func (e *ageEncryptor) Encrypt(src string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	dst := src + ".age"
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	w, err := age.Encrypt(out, e.recipients...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return dst, nil
+}
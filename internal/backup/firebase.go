@@ -0,0 +1,36 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/hyperjumptech/bookkeeping/internal/firebase"
+)
+
+// FirebaseDestination ships dumps to Firebase storage using the existing firebase package.
+type FirebaseDestination struct{}
+
+// NewFirebaseDestination creates a Destination backed by firebase.Upload.
+func NewFirebaseDestination() *FirebaseDestination {
+	return &FirebaseDestination{}
+}
+
+// Name implements Destination.
+func (d *FirebaseDestination) Name() string {
+	return "firebase"
+}
+
+// Upload implements Destination. remoteName is not used: firebase.Upload is
+// given only localPath.
+func (d *FirebaseDestination) Upload(ctx context.Context, localPath string, remoteName string) error {
+	return firebase.Upload(ctx, localPath)
+}
+
+// List implements Destination. firebase does not currently expose a listing API.
+func (d *FirebaseDestination) List(ctx context.Context, jobName string) ([]Artifact, error) {
+	return nil, &ErrNotSupported{Destination: d.Name(), Op: "List"}
+}
+
+// Delete implements Destination. firebase does not currently expose a delete API.
+func (d *FirebaseDestination) Delete(ctx context.Context, remoteName string) error {
+	return &ErrNotSupported{Destination: d.Name(), Op: "Delete"}
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperjumptech/bookkeeping/internal/config"
+)
+
+// RegisterAdminRoutes wires the on-demand trigger and artifact listing
+// endpoints onto r, guarded by the configured admin token.
+func RegisterAdminRoutes(r *mux.Router, sched *BackupScheduler) {
+	r.HandleFunc("/admin/backup/run", requireAdminToken(runHandler(sched))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/backup/list", requireAdminToken(listHandler(sched))).Methods(http.MethodGet)
+}
+
+func requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := config.Get("admin.token")
+		presented, hasBearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || !hasBearer || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func runHandler(sched *BackupScheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("job")
+		if name == "" {
+			http.Error(w, "missing required query param: job", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		flusher, canFlush := w.(http.Flusher)
+
+		progress := func(msg string) {
+			fmt.Fprintf(w, "%s\n", msg)
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+
+		if err := sched.Run(r.Context(), name, progress); err != nil {
+			fmt.Fprintf(w, "error: %s\n", err)
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func listHandler(sched *BackupScheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("job")
+		if name == "" {
+			http.Error(w, "missing required query param: job", http.StatusBadRequest)
+			return
+		}
+
+		artifacts, err := sched.List(r.Context(), name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(artifacts)
+	}
+}
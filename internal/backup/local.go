@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LocalDestination stores backups on the local filesystem, useful for
+// development and as a staging area before a remote upload.
+type LocalDestination struct {
+	Dir string
+}
+
+// NewLocalDestination creates a Destination rooted at dir, creating it if needed.
+func NewLocalDestination(dir string) (*LocalDestination, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalDestination{Dir: dir}, nil
+}
+
+// Name implements Destination.
+func (d *LocalDestination) Name() string {
+	return "local"
+}
+
+// Upload implements Destination.
+func (d *LocalDestination) Upload(ctx context.Context, localPath string, remoteName string) error {
+	dst := filepath.Join(d.Dir, remoteName)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
+
+// List implements Destination. It only walks the named job's subdirectory
+// (the same "<jobName>/..." layout Upload writes under), so jobs sharing a
+// Destination never see each other's artifacts.
+func (d *LocalDestination) List(ctx context.Context, jobName string) ([]Artifact, error) {
+	root := filepath.Join(d.Dir, jobName)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var artifacts []Artifact
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(d.Dir, path)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:       rel,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].ModifiedAt.After(artifacts[j].ModifiedAt)
+	})
+	return artifacts, nil
+}
+
+// Delete implements Destination.
+func (d *LocalDestination) Delete(ctx context.Context, remoteName string) error {
+	return os.Remove(filepath.Join(d.Dir, remoteName))
+}
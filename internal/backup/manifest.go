@@ -0,0 +1,37 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+)
+
+// Manifest records the checksum of a single backup artifact so consumers can
+// verify integrity after download.
+type Manifest struct {
+	File   string `json:"file"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// buildManifest computes the SHA256 checksum of path and returns the manifest for it.
+func buildManifest(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{
+		File:   path,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+		Size:   size,
+	}, nil
+}
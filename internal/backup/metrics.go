@@ -0,0 +1,20 @@
+package backup
+
+// LeaderElector reports whether this process currently holds leadership,
+// e.g. *github.com/hyperjumptech/bookkeeping/internal/leader.MySQLElector.
+// Scheduled runs are skipped on followers so replicas don't all back up at once.
+type LeaderElector interface {
+	IsLeader() bool
+}
+
+// Metrics receives observations about job runs. Implementations are expected
+// to be safe for concurrent use; a nil Metrics on BackupScheduler simply
+// means no observations are recorded.
+type Metrics interface {
+	// ObserveDuration records how long a job run took, in seconds.
+	ObserveDuration(job string, seconds float64)
+	// SetLastSuccess records the unix timestamp of a job's last successful run.
+	SetLastSuccess(job string, unixSeconds float64)
+	// IncFailure records a failed job run.
+	IncFailure(job string)
+}
@@ -0,0 +1,30 @@
+package backup
+
+import "time"
+
+// Retention describes how many backups to keep at a destination.
+type Retention struct {
+	// KeepLatest keeps the N most recent artifacts regardless of age. 0 disables this rule.
+	KeepLatest int
+	// MaxAge prunes artifacts older than this duration. 0 disables this rule.
+	MaxAge time.Duration
+}
+
+// prune returns the names of artifacts that should be deleted under r, given
+// artifacts sorted most-recent-first.
+func (r Retention) prune(artifacts []Artifact, now time.Time) []string {
+	var toDelete []string
+	for i, a := range artifacts {
+		keep := false
+		if r.KeepLatest > 0 && i < r.KeepLatest {
+			keep = true
+		}
+		if r.MaxAge > 0 && now.Sub(a.ModifiedAt) <= r.MaxAge {
+			keep = true
+		}
+		if !keep {
+			toDelete = append(toDelete, a.Name)
+		}
+	}
+	return toDelete
+}
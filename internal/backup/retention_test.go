@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetentionPruneKeepLatest(t *testing.T) {
+	now := time.Now()
+	artifacts := []Artifact{
+		{Name: "daily-full/3.sql.gz", ModifiedAt: now},
+		{Name: "daily-full/2.sql.gz", ModifiedAt: now.Add(-24 * time.Hour)},
+		{Name: "daily-full/1.sql.gz", ModifiedAt: now.Add(-48 * time.Hour)},
+	}
+
+	r := Retention{KeepLatest: 2}
+	got := r.prune(artifacts, now)
+	want := []string{"daily-full/1.sql.gz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("prune() = %v, want %v", got, want)
+	}
+}
+
+func TestRetentionPruneMaxAge(t *testing.T) {
+	now := time.Now()
+	artifacts := []Artifact{
+		{Name: "hourly-incremental/new.sql.gz", ModifiedAt: now},
+		{Name: "hourly-incremental/old.sql.gz", ModifiedAt: now.Add(-72 * time.Hour)},
+	}
+
+	r := Retention{MaxAge: 24 * time.Hour}
+	got := r.prune(artifacts, now)
+	want := []string{"hourly-incremental/old.sql.gz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("prune() = %v, want %v", got, want)
+	}
+}
+
+// TestRetentionPruneDoesNotCrossJobs guards against regressing the bug where
+// two jobs sharing a Destination had their artifacts listed together: a
+// daily job's KeepLatest: 2 must only ever be evaluated against that job's
+// own artifacts, never an hourly job's far more numerous ones.
+func TestRetentionPruneDoesNotCrossJobs(t *testing.T) {
+	now := time.Now()
+	d, err := NewLocalDestination(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalDestination() error = %v", err)
+	}
+	// both hourly artifacts are newer than daily-full/2.sql.gz
+	ages := map[string]time.Duration{
+		"daily-full/3.sql.gz":         0,
+		"daily-full/2.sql.gz":         24 * time.Hour,
+		"daily-full/1.sql.gz":         48 * time.Hour,
+		"hourly-incremental/b.sql.gz": time.Hour,
+		"hourly-incremental/a.sql.gz": 2 * time.Hour,
+	}
+	for name, age := range ages {
+		p := filepath.Join(d.Dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dailyOnly, err := d.List(context.Background(), "daily-full")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	r := Retention{KeepLatest: 2}
+	got := r.prune(dailyOnly, now)
+	want := []string{"daily-full/1.sql.gz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("prune() over a job-scoped artifact list = %v, want %v; "+
+			"a Destination.List that mixes in another job's artifacts would "+
+			"let its newer entries crowd daily-full/2.sql.gz out of KeepLatest", got, want)
+	}
+}
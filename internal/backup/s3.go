@@ -0,0 +1,108 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Destination ships backups to any S3-compatible object store (AWS S3, MinIO, etc).
+type S3Destination struct {
+	Bucket   string
+	Prefix   string
+	Endpoint string
+	client   *s3.Client
+}
+
+// NewS3Destination builds a Destination talking to the given bucket. If endpoint is
+// non-empty the client is pointed at an S3-compatible endpoint instead of AWS.
+func NewS3Destination(ctx context.Context, bucket, prefix, endpoint string) (*S3Destination, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("backup: loading s3 config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3Destination{Bucket: bucket, Prefix: prefix, Endpoint: endpoint, client: client}, nil
+}
+
+// Name implements Destination.
+func (d *S3Destination) Name() string {
+	return "s3"
+}
+
+func (d *S3Destination) key(remoteName string) string {
+	if d.Prefix == "" {
+		return remoteName
+	}
+	return d.Prefix + "/" + remoteName
+}
+
+// Upload implements Destination.
+func (d *S3Destination) Upload(ctx context.Context, localPath string, remoteName string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = d.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(remoteName)),
+		Body:   f,
+	})
+	return err
+}
+
+// List implements Destination. It only lists objects under the named job's
+// key prefix (the same "<jobName>/..." layout Upload writes under), so jobs
+// sharing a Destination never see each other's artifacts. Artifact names are
+// returned without Prefix, the same form Upload and Delete take.
+func (d *S3Destination) List(ctx context.Context, jobName string) ([]Artifact, error) {
+	out, err := d.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.Bucket),
+		Prefix: aws.String(d.key(jobName) + "/"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(out.Contents))
+	for _, obj := range out.Contents {
+		name := aws.ToString(obj.Key)
+		if d.Prefix != "" {
+			name = strings.TrimPrefix(name, d.Prefix+"/")
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:       name,
+			Size:       aws.ToInt64(obj.Size),
+			ModifiedAt: aws.ToTime(obj.LastModified),
+		})
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].ModifiedAt.After(artifacts[j].ModifiedAt)
+	})
+	return artifacts, nil
+}
+
+// Delete implements Destination.
+func (d *S3Destination) Delete(ctx context.Context, remoteName string) error {
+	_, err := d.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(remoteName)),
+	})
+	return err
+}
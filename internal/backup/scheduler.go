@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+var schedLog = log.WithField("module", "backup")
+
+// DumpFunc produces a local dump file to be shipped by a Job, e.g. dbRepo.DumpDB.
+type DumpFunc func(ctx context.Context) (string, error)
+
+// Job is a single scheduled backup: where to dump, where to send it, and how
+// long to keep copies once they arrive.
+type Job struct {
+	// Name identifies the job in logs and the admin API, e.g. "hourly-incremental"
+	Name string
+	// CronSpec is a standard cron expression, e.g. "0 * * * *"
+	CronSpec string
+	// Destination is where the dump is uploaded to.
+	Destination Destination
+	// Retention governs pruning of old artifacts at Destination after a successful upload.
+	Retention Retention
+	// Encryptor, if set, encrypts the dump before it is uploaded.
+	Encryptor Encryptor
+}
+
+// BackupScheduler runs one or more Jobs on independent cron schedules against
+// a shared DumpFunc.
+type BackupScheduler struct {
+	cr   *cron.Cron
+	dump DumpFunc
+
+	// Metrics, if set, is notified of each job run's duration and outcome.
+	Metrics Metrics
+
+	// Elector, if set, gates scheduled runs: only the current leader executes
+	// a job when its cron spec fires, so replicas of the service don't all
+	// dump and upload at once. On-demand runs via Run are not gated, since
+	// they are an explicit operator action. A nil Elector means every
+	// replica runs its schedule, which is correct for single-instance deployments.
+	Elector LeaderElector
+
+	mu      sync.Mutex
+	jobs    map[string]Job
+	entries map[string]cron.EntryID
+}
+
+// NewBackupScheduler creates a scheduler that drives cr and dumps via dump.
+func NewBackupScheduler(cr *cron.Cron, dump DumpFunc) *BackupScheduler {
+	return &BackupScheduler{
+		cr:      cr,
+		dump:    dump,
+		jobs:    make(map[string]Job),
+		entries: make(map[string]cron.EntryID),
+	}
+}
+
+// Register adds job to the scheduler and schedules it on its cron spec.
+func (s *BackupScheduler) Register(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("backup: job %q already registered", job.Name)
+	}
+
+	id, err := s.cr.AddFunc(job.CronSpec, func() {
+		if s.Elector != nil && !s.Elector.IsLeader() {
+			schedLog.WithField("job", job.Name).Info("skipping scheduled run: not leader")
+			return
+		}
+		if err := s.Run(context.Background(), job.Name, nil); err != nil {
+			schedLog.WithField("job", job.Name).Error("scheduled backup failed: ", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("backup: scheduling job %q: %w", job.Name, err)
+	}
+
+	s.jobs[job.Name] = job
+	s.entries[job.Name] = id
+	return nil
+}
+
+// Unregister removes the named job's cron entry so it no longer fires. It is
+// a no-op if the job is not registered, which makes it safe to call as part
+// of a reschedule (unregister, then Register again with a fresh CronSpec).
+func (s *BackupScheduler) Unregister(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.entries[name]; ok {
+		s.cr.Remove(id)
+	}
+	delete(s.entries, name)
+	delete(s.jobs, name)
+}
+
+// Jobs returns the names of all registered jobs.
+func (s *BackupScheduler) Jobs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Run executes the named job once, immediately. progress, if non-nil, is
+// called with a short human-readable message as each step begins, and with
+// "done" once the job has finished, so callers
+// (e.g. the on-demand HTTP trigger) can stream status to the caller.
+func (s *BackupScheduler) Run(ctx context.Context, name string, progress func(string)) (err error) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("backup: job %q not registered", name)
+	}
+
+	start := time.Now()
+	defer func() {
+		if s.Metrics == nil {
+			return
+		}
+		s.Metrics.ObserveDuration(name, time.Since(start).Seconds())
+		if err != nil {
+			s.Metrics.IncFailure(name)
+		} else {
+			s.Metrics.SetLastSuccess(name, float64(time.Now().Unix()))
+		}
+	}()
+
+	report := func(msg string) {
+		schedLog.WithField("job", name).Info(msg)
+		if progress != nil {
+			progress(msg)
+		}
+	}
+
+	report("dumping database")
+	file, err := s.dump(ctx)
+	if err != nil {
+		return fmt.Errorf("backup: dump failed: %w", err)
+	}
+	defer os.Remove(file)
+
+	uploadPath := file
+	if job.Encryptor != nil {
+		report("encrypting dump")
+		uploadPath, err = job.Encryptor.Encrypt(file)
+		if err != nil {
+			return fmt.Errorf("backup: encryption failed: %w", err)
+		}
+		defer os.Remove(uploadPath)
+	}
+
+	report("computing checksum")
+	manifest, err := buildManifest(uploadPath)
+	if err != nil {
+		return fmt.Errorf("backup: manifest failed: %w", err)
+	}
+
+	remoteName := fmt.Sprintf("%s/%s", name, filepath.Base(uploadPath))
+	report(fmt.Sprintf("uploading to %s (sha256 %s)", job.Destination.Name(), manifest.SHA256))
+	if err := job.Destination.Upload(ctx, uploadPath, remoteName); err != nil {
+		return fmt.Errorf("backup: upload failed: %w", err)
+	}
+
+	if job.Retention.KeepLatest > 0 || job.Retention.MaxAge > 0 {
+		report("applying retention policy")
+		if err := s.prune(ctx, job); err != nil {
+			// a pruning failure should not fail the backup itself
+			schedLog.WithField("job", name).Warn("retention prune failed: ", err)
+		}
+	}
+
+	report("done")
+	return nil
+}
+
+func (s *BackupScheduler) prune(ctx context.Context, job Job) error {
+	artifacts, err := job.Destination.List(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range job.Retention.prune(artifacts, time.Now()) {
+		if err := job.Destination.Delete(ctx, name); err != nil {
+			return fmt.Errorf("pruning %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// List enumerates the remote artifacts stored for the named job.
+func (s *BackupScheduler) List(ctx context.Context, name string) ([]Artifact, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("backup: job %q not registered", name)
+	}
+
+	return job.Destination.List(ctx, job.Name)
+}
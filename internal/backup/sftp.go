@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTPDestination ships backups to a remote host over SFTP.
+type SFTPDestination struct {
+	Dir    string
+	client *sftp.Client
+	conn   *ssh.Client
+}
+
+// NewSFTPDestination dials addr and authenticates with the given ssh config,
+// storing artifacts under dir on the remote host.
+func NewSFTPDestination(addr string, sshCfg *ssh.ClientConfig, dir string) (*SFTPDestination, error) {
+	conn, err := ssh.Dial("tcp", addr, sshCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err := client.MkdirAll(dir); err != nil {
+		client.Close()
+		conn.Close()
+		return nil, err
+	}
+
+	return &SFTPDestination{Dir: dir, client: client, conn: conn}, nil
+}
+
+// Close releases the underlying sftp and ssh connections.
+func (d *SFTPDestination) Close() error {
+	_ = d.client.Close()
+	return d.conn.Close()
+}
+
+// Name implements Destination.
+func (d *SFTPDestination) Name() string {
+	return "sftp"
+}
+
+// Upload implements Destination.
+func (d *SFTPDestination) Upload(ctx context.Context, localPath string, remoteName string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	remotePath := path.Join(d.Dir, remoteName)
+	if err := d.client.MkdirAll(path.Dir(remotePath)); err != nil {
+		return err
+	}
+
+	dst, err := d.client.Create(remotePath)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = dst.ReadFrom(src)
+	return err
+}
+
+// List implements Destination. It reads the named job's subdirectory (the
+// same "<jobName>/..." layout Upload writes under) rather than d.Dir itself,
+// so jobs sharing a Destination never see each other's artifacts.
+func (d *SFTPDestination) List(ctx context.Context, jobName string) ([]Artifact, error) {
+	dir := path.Join(d.Dir, jobName)
+	entries, err := d.client.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	artifacts := make([]Artifact, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:       path.Join(jobName, e.Name()),
+			Size:       e.Size(),
+			ModifiedAt: e.ModTime(),
+		})
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].ModifiedAt.After(artifacts[j].ModifiedAt)
+	})
+	return artifacts, nil
+}
+
+// Delete implements Destination.
+func (d *SFTPDestination) Delete(ctx context.Context, remoteName string) error {
+	return d.client.Remove(path.Join(d.Dir, remoteName))
+}
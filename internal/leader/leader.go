@@ -0,0 +1,145 @@
+// Package leader provides distributed leader election so that only one
+// replica of a horizontally-scaled service runs singleton work, such as the
+// backup cron schedule.
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var electLog = log.WithField("module", "leader")
+
+// Elector reports whether the current process currently holds leadership.
+// A process that is not the leader should skip singleton work and let
+// whichever replica holds the lock run it instead.
+type Elector interface {
+	// IsLeader reports whether this process currently holds the lock.
+	IsLeader() bool
+}
+
+// MySQLElector elects a leader using MySQL's GET_LOCK/RELEASE_LOCK, which is
+// scoped to the connection that acquired it - so the elector holds a single
+// dedicated *sql.Conn open for as long as it wants to compete for leadership.
+// On a ticker it checks that it still holds the lock while leader, and
+// re-attempts GET_LOCK while follower (e.g. after the lock was lost because
+// the connection was killed by the server).
+type MySQLElector struct {
+	db              *sql.DB
+	lockName        string
+	refreshInterval time.Duration
+
+	mu     sync.RWMutex
+	conn   *sql.Conn
+	leader bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewMySQLElector builds an elector that competes for lockName on db,
+// re-attempting acquisition every refreshInterval.
+func NewMySQLElector(db *sql.DB, lockName string, refreshInterval time.Duration) *MySQLElector {
+	return &MySQLElector{
+		db:              db,
+		lockName:        lockName,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// Start dedicates a connection to the lock and begins competing for
+// leadership in the background. Call Stop to release the lock and return
+// the connection to the pool.
+func (e *MySQLElector) Start(ctx context.Context) error {
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("leader: acquiring dedicated connection: %w", err)
+	}
+
+	e.mu.Lock()
+	e.conn = conn
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	e.mu.Unlock()
+
+	go e.run(ctx)
+	return nil
+}
+
+func (e *MySQLElector) run(ctx context.Context) {
+	defer close(e.done)
+
+	e.tryAcquire(ctx)
+
+	ticker := time.NewTicker(e.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.tryAcquire(ctx)
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+func (e *MySQLElector) tryAcquire(ctx context.Context) {
+	// GET_LOCK nests when this session already holds the lock, and one
+	// RELEASE_LOCK undoes only one level, so a held lock is checked rather
+	// than acquired again; that keeps Stop's single RELEASE_LOCK sufficient.
+	query := "SELECT GET_LOCK(?, 0)"
+	if e.IsLeader() {
+		query = "SELECT IS_USED_LOCK(?) = CONNECTION_ID()"
+	}
+	var acquired sql.NullInt64
+	err := e.conn.QueryRowContext(ctx, query, e.lockName).Scan(&acquired)
+
+	leader := err == nil && acquired.Valid && acquired.Int64 == 1
+	if err != nil {
+		electLog.WithField("lock", e.lockName).Warn("leader election query failed, assuming follower: ", err)
+	}
+
+	e.mu.Lock()
+	e.leader = leader
+	e.mu.Unlock()
+
+	if leader {
+		electLog.WithField("lock", e.lockName).Debug("holding leader lock")
+	} else {
+		electLog.WithField("lock", e.lockName).Debug("running as follower")
+	}
+}
+
+// IsLeader implements Elector.
+func (e *MySQLElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Stop releases the lock (if held) and returns the dedicated connection to
+// the pool. It should be called before the pool itself is closed.
+func (e *MySQLElector) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	stop, conn := e.stop, e.conn
+	e.mu.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	<-e.done
+
+	_, relErr := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", e.lockName)
+	closeErr := conn.Close()
+	if relErr != nil {
+		return fmt.Errorf("leader: releasing lock: %w", relErr)
+	}
+	return closeErr
+}
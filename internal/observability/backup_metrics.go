@@ -0,0 +1,21 @@
+package observability
+
+// BackupMetrics adapts the package-level backup metric collectors to the
+// backup.Metrics interface, so internal/backup stays free of a dependency on
+// the prometheus client library.
+type BackupMetrics struct{}
+
+// ObserveDuration implements backup.Metrics.
+func (BackupMetrics) ObserveDuration(job string, seconds float64) {
+	BackupDuration.WithLabelValues(job).Observe(seconds)
+}
+
+// SetLastSuccess implements backup.Metrics.
+func (BackupMetrics) SetLastSuccess(job string, unixSeconds float64) {
+	BackupLastSuccessTimestamp.WithLabelValues(job).Set(unixSeconds)
+}
+
+// IncFailure implements backup.Metrics.
+func (BackupMetrics) IncFailure(job string) {
+	BackupFailuresTotal.WithLabelValues(job).Inc()
+}
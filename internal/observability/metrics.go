@@ -0,0 +1,57 @@
+// Package observability stands up a second, unexported-to-the-public
+// introspection listener exposing Prometheus metrics, pprof profiles, and
+// liveness/readiness probes, kept off the main API's bind address.
+package observability
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	// RequestsTotal counts completed HTTP requests by route template and status.
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests processed, labeled by route and status.",
+	}, []string{"route", "method", "status"})
+
+	// RequestDuration observes request latency by route template and status.
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, labeled by route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// RequestsInFlight tracks requests currently being served, labeled by route template.
+	RequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served, labeled by route.",
+	}, []string{"route", "method"})
+
+	// BackupDuration observes how long a backup job took to dump, encrypt, and upload.
+	BackupDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "backup_duration_seconds",
+		Help:    "Duration of a backup job run in seconds, labeled by job name.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"job"})
+
+	// BackupLastSuccessTimestamp records the unix time of a job's last successful run.
+	BackupLastSuccessTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "backup_last_success_timestamp_seconds",
+		Help: "Unix timestamp of the last successful backup run, labeled by job name.",
+	}, []string{"job"})
+
+	// BackupFailuresTotal counts failed backup job runs, labeled by job name.
+	BackupFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "backup_failures_total",
+		Help: "Total number of failed backup job runs, labeled by job name.",
+	}, []string{"job"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		RequestsTotal,
+		RequestDuration,
+		RequestsInFlight,
+		BackupDuration,
+		BackupLastSuccessTimestamp,
+		BackupFailuresTotal,
+	)
+}
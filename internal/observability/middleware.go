@@ -0,0 +1,64 @@
+package observability
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// statusRecorder wraps a ResponseWriter so Middleware can observe the status
+// code a handler actually wrote, defaulting to 200 if WriteHeader is never called.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush passes through to the wrapped ResponseWriter, so handlers that
+// stream their response (e.g. the backup run trigger) can still flush.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Middleware instruments every request passing through it with
+// RequestsTotal, RequestDuration, and RequestsInFlight, labeled by the
+// matched mux route template rather than the raw path so that templated
+// routes (e.g. "/accounts/{id}") don't blow up label cardinality.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		method := r.Method
+
+		inFlight := RequestsInFlight.WithLabelValues(route, method)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		elapsed := time.Since(start).Seconds()
+
+		status := fmt.Sprintf("%d", rec.status)
+		RequestsTotal.WithLabelValues(route, method, status).Inc()
+		RequestDuration.WithLabelValues(route, method, status).Observe(elapsed)
+	})
+}
+
+// routeTemplate returns the matched mux route's template path, or "unmatched"
+// when the router found no route (e.g. a 404).
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return "unmatched"
+}
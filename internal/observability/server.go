@@ -0,0 +1,64 @@
+package observability
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ReadyFunc reports whether the service is ready to take traffic, e.g. a db ping.
+type ReadyFunc func() error
+
+// LeaderStatusFunc reports whether this replica currently holds the
+// distributed cron leader lock. nil means leader election is not in use.
+type LeaderStatusFunc func() bool
+
+// NewIntrospectionServer builds (but does not start) the second http.Server
+// exposing /metrics, /debug/pprof/*, /healthz, and /readyz on addr. addr
+// should be bound to a private/internal interface only - this listener is
+// not meant to be scraped from outside the cluster. leader, if non-nil, is
+// surfaced on /healthz so an operator can see which replica owns the
+// backup schedule.
+func NewIntrospectionServer(addr string, ready ReadyFunc, leader LeaderStatusFunc) *http.Server {
+	mux := http.NewServeMux()
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{"status": "ok"}
+		if leader != nil {
+			body["leader"] = leader()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(body)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if ready == nil {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("ok"))
+			return
+		}
+		if err := ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/hyperjumptech/bookkeeping/internal/backup"
+	"github.com/hyperjumptech/bookkeeping/internal/config"
+	"github.com/hyperjumptech/bookkeeping/internal/logger"
+	"github.com/hyperjumptech/bookkeeping/internal/observability"
+	"github.com/hyperjumptech/bookkeeping/internal/router"
+	"github.com/robfig/cron/v3"
+)
+
+// restartOnlyKeys are config keys that cannot be hot-swapped by reloadConfig
+// because the subsystems reading them only consult the value at connection
+// time (the TCP listener's bind address, the db DSN). Changing any of these
+// in the config file is logged and otherwise ignored until the next restart.
+var restartOnlyKeys = []string{
+	"server.host",
+	"server.port",
+	"db.dsn",
+	"server.introspection.host",
+	"server.introspection.port",
+	"server.tls.mode",
+	"server.tls.hosts",
+	"server.tls.cache_dir",
+	"server.tls.email",
+	"server.tls.cert_file",
+	"server.tls.key_file",
+	"leader.election.refresh_interval_seconds",
+}
+
+// reloadConfig re-reads config on SIGHUP and hot-swaps everything that can be
+// hot-swapped: log level, the backup cron schedule, and the HTTP router.
+// Values that require a restart are diffed against their pre-reload value and
+// logged rather than applied.
+func reloadConfig() {
+	logf := srvLog.WithField("fn", "reloadConfig")
+	logf.Info("SIGHUP received, reloading config...")
+
+	before := make(map[string]string, len(restartOnlyKeys))
+	for _, key := range restartOnlyKeys {
+		before[key] = config.Get(key)
+	}
+
+	config.LoadConfig()
+	logger.ConfigureLogging()
+
+	for _, key := range restartOnlyKeys {
+		if after := config.Get(key); after != before[key] {
+			logf.Warnf("%s changed (%q -> %q) but requires restart, keeping old value in effect", key, before[key], after)
+		}
+	}
+
+	reloadBackupSchedule()
+	reloadRouter()
+
+	logf.Info("config reload complete")
+}
+
+// reloadBackupSchedule re-parses the cron schedule for the backup jobs,
+// removing and re-adding their entries on cr so a changed
+// cron.backup.daily/hourly spec takes effect without a restart. The new job
+// set (destination, encryptor, retention, cron specs) is built and validated
+// in full before anything is unregistered from backupSched - the same way
+// reloadRouter builds newRouter fully before swapping routerPtr - so a bad
+// backup.destination or unreadable SFTP key on reload leaves the previous,
+// working schedule in place instead of disabling backups until a restart.
+func reloadBackupSchedule() {
+	logf := srvLog.WithField("fn", "reloadBackupSchedule")
+
+	if backupSched == nil {
+		return
+	}
+
+	jobs, err := buildBackupJobs()
+	if err != nil {
+		logf.Error("failed to build new backup job set, keeping previous schedule in effect: ", err)
+		return
+	}
+	// cr was built with cron.New(), whose parser is the standard one
+	for _, job := range jobs {
+		if _, err := cron.ParseStandard(job.CronSpec); err != nil {
+			logf.Error("invalid cron spec for backup job ", job.Name, ", keeping previous schedule in effect: ", err)
+			return
+		}
+	}
+
+	backupSched.Unregister("daily-full")
+	backupSched.Unregister("hourly-incremental")
+
+	for _, job := range jobs {
+		if err := backupSched.Register(job); err != nil {
+			logf.Error("failed to register backup job ", job.Name, ": ", err)
+		}
+	}
+}
+
+// reloadRouter builds a fresh router from the reloaded config/middleware and
+// swaps it into routerPtr, so in-flight requests keep being served by the
+// old router while new requests pick up the new one.
+func reloadRouter() {
+	logf := srvLog.WithField("fn", "reloadRouter")
+
+	newRouter := router.NewRouter()
+	newRouter.Router = mux.NewRouter()
+	newRouter.Router.Use(observability.Middleware)
+	router.InitRoutes(newRouter)
+	if backupSched != nil {
+		backup.RegisterAdminRoutes(newRouter.Router, backupSched)
+	}
+
+	appRouter = newRouter
+	routerPtr.Store(newRouter.Router)
+	logf.Info("router swapped in")
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var shutdownLog = log.WithField("module", "shutdown")
+
+// shutdownHookFunc is run during Server.Shutdown. It receives a context
+// carrying the per-hook timeout and should return promptly once ctx is done.
+type shutdownHookFunc func(context.Context) error
+
+type shutdownHook struct {
+	name    string
+	fn      shutdownHookFunc
+	timeout time.Duration
+}
+
+// Server coordinates graceful shutdown across subsystems that register
+// themselves via AddShutdownHook. It mirrors net/http.Server's
+// RegisterOnShutdown in spirit, but runs hooks in reverse registration order
+// (last registered, first stopped), driving the whole sequence from a single
+// context.WithTimeout (see shutdownServer's use of server.shutdown.timeout)
+// rather than giving each hook its own fixed budget.
+type Server struct {
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+// AddShutdownHook registers fn to run during Shutdown, under name for
+// logging. Hooks run in reverse registration order, so the subsystem started
+// last (and therefore most likely to depend on ones before it) is stopped
+// first. The hook is bounded only by whatever's left of Shutdown's ctx - use
+// AddShutdownHookWithTimeout to additionally cap an individual hook.
+func (s *Server) AddShutdownHook(name string, fn shutdownHookFunc) {
+	s.AddShutdownHookWithTimeout(name, fn, 0)
+}
+
+// AddShutdownHookWithTimeout is AddShutdownHook with an explicit per-hook
+// timeout, carved out of (not added to) whatever's left of Shutdown's ctx. A
+// timeout of 0 means "no extra cap, just use the remaining ctx budget".
+func (s *Server) AddShutdownHookWithTimeout(name string, fn shutdownHookFunc, timeout time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, fn: fn, timeout: timeout})
+}
+
+// RegisterOnShutdown registers fn the way net/http.Server.RegisterOnShutdown
+// does: fire-and-forget, no error return, no per-hook timeout. Provided for
+// subsystems migrating straight off the stdlib API.
+func (s *Server) RegisterOnShutdown(fn func()) {
+	s.AddShutdownHook("onShutdown", func(ctx context.Context) error {
+		go fn()
+		return nil
+	})
+}
+
+// Shutdown runs every registered hook in reverse registration order. Each
+// hook gets whatever time is left on ctx's deadline - the same single
+// context.WithTimeout(server.shutdown.timeout) the whole drain runs under -
+// optionally capped further by a per-hook timeout from
+// AddShutdownHookWithTimeout. It keeps going on error so one failing
+// subsystem does not block the others from draining, and returns the first
+// error encountered, if any.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := make([]shutdownHook, len(s.hooks))
+	copy(hooks, s.hooks)
+	s.mu.Unlock()
+
+	var firstErr error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		logf := shutdownLog.WithField("hook", h.name)
+
+		var hookCtx context.Context
+		var cancel context.CancelFunc
+		if h.timeout > 0 {
+			hookCtx, cancel = context.WithTimeout(ctx, h.timeout)
+		} else {
+			hookCtx, cancel = context.WithCancel(ctx)
+		}
+		err := h.fn(hookCtx)
+		cancel()
+
+		if err != nil {
+			logf.Error("shutdown hook failed: ", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logf.Info("shutdown hook done")
+	}
+	return firstErr
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestServerShutdownRunsHooksInReverseOrder(t *testing.T) {
+	var order []string
+	s := &Server{}
+	s.AddShutdownHook("db", func(ctx context.Context) error {
+		order = append(order, "db")
+		return nil
+	})
+	s.AddShutdownHook("http", func(ctx context.Context) error {
+		order = append(order, "http")
+		return nil
+	})
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	want := []string{"http", "db"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Fatalf("hook order = %v, want %v", order, want)
+	}
+}
+
+func TestServerShutdownHookInheritsRemainingBudget(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	s := &Server{}
+	var deadlineSeen bool
+	s.AddShutdownHook("cron", func(hookCtx context.Context) error {
+		_, deadlineSeen = hookCtx.Deadline()
+		return nil
+	})
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !deadlineSeen {
+		t.Fatal("hook context did not inherit the overall shutdown deadline")
+	}
+}
+
+func TestServerShutdownHookWithTimeoutCapsBelowParent(t *testing.T) {
+	parentDeadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), parentDeadline)
+	defer cancel()
+
+	s := &Server{}
+	var hookDeadline time.Time
+	s.AddShutdownHookWithTimeout("introspection", func(hookCtx context.Context) error {
+		hookDeadline, _ = hookCtx.Deadline()
+		return nil
+	}, 5*time.Second)
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !hookDeadline.Before(parentDeadline) {
+		t.Fatalf("hook deadline %v should be capped well before the parent deadline %v", hookDeadline, parentDeadline)
+	}
+}
+
+func TestServerShutdownContinuesAfterHookError(t *testing.T) {
+	var ranFirst bool
+	s := &Server{}
+	s.AddShutdownHook("first", func(ctx context.Context) error {
+		ranFirst = true
+		return nil
+	})
+	// registered last, so it runs first and fails before "first" runs
+	s.AddShutdownHook("second", func(ctx context.Context) error {
+		return context.DeadlineExceeded
+	})
+
+	err := s.Shutdown(context.Background())
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Shutdown() error = %v, want the failing hook's error", err)
+	}
+	if !ranFirst {
+		t.Fatal("a failing hook should not prevent earlier-registered hooks from running")
+	}
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperjumptech/bookkeeping/internal/config"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var tlsLog = log.WithField("module", "tls")
+
+// configureTLS wires HTTPServer up for TLS according to server.tls.mode:
+//   - "off" (default): plain HTTP, unchanged from before.
+//   - "manual": HTTPServer.TLSConfig is left nil; StartServer calls
+//     ListenAndServeTLS with server.tls.cert_file/server.tls.key_file.
+//   - "autocert": obtains and rotates certificates from Let's Encrypt via
+//     HTTP-01. Returns a second *http.Server on :80 that must be started
+//     alongside HTTPServer to answer the ACME challenge and redirect
+//     plain HTTP traffic to HTTPS.
+//
+// The autocert cache_dir is expected to live on the same persistent volume
+// as the backup destination's local staging directory, so certificates
+// survive a restart instead of being re-issued against Let's Encrypt's rate limits.
+func configureTLS() *http.Server {
+	mode := config.Get("server.tls.mode")
+
+	switch mode {
+	case "autocert":
+		hosts := splitCSV(config.Get("server.tls.hosts"))
+		cacheDir := config.Get("server.tls.cache_dir")
+
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(hosts...),
+			Cache:      autocert.DirCache(cacheDir),
+			Email:      config.Get("server.tls.email"),
+		}
+
+		HTTPServer.TLSConfig = m.TLSConfig()
+		tlsLog.Info("autocert enabled for hosts: ", hosts, ", cache dir: ", filepath.Clean(cacheDir))
+
+		return &http.Server{
+			Addr:    ":80",
+			Handler: m.HTTPHandler(nil),
+		}
+	case "manual":
+		tlsLog.Info("manual TLS enabled, using server.tls.cert_file/server.tls.key_file")
+		return nil
+	default:
+		return nil
+	}
+}
+
+// splitCSV splits a comma-separated config value into trimmed, non-empty
+// entries. Shared by the autocert host whitelist and the backup encryption
+// recipient list.
+func splitCSV(raw string) []string {
+	var entries []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			entries = append(entries, v)
+		}
+	}
+	return entries
+}